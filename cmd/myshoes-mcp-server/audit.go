@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/viper"
+
+	"github.com/goccy/go-json"
+)
+
+// AuditRecord is the structured record emitted for every tool invocation.
+type AuditRecord struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	Tool         string          `json:"tool"`
+	SessionID    string          `json:"session_id,omitempty"`
+	Input        json.RawMessage `json:"input,omitempty"`
+	MyshoesState int             `json:"myshoes_status,omitempty"`
+	DurationMS   int64           `json:"duration_ms"`
+	Outcome      string          `json:"outcome"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// AuditSink is a pluggable destination for audit records.
+type AuditSink interface {
+	Write(record AuditRecord) error
+}
+
+// auditRedactor replaces the value of each configured top-level field of a
+// tool's input with "[redacted]" before it is written to an audit record, so
+// secrets that happen to be tool arguments never reach the sink. Field names
+// match case-insensitively; nested objects are not searched.
+type auditRedactor struct {
+	fields map[string]bool
+}
+
+func newAuditRedactor(fields []string) *auditRedactor {
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[strings.ToLower(f)] = true
+	}
+	return &auditRedactor{fields: set}
+}
+
+func (r *auditRedactor) redact(input any) json.RawMessage {
+	jb, err := json.Marshal(input)
+	if err != nil || len(r.fields) == 0 {
+		return jb
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(jb, &m); err != nil {
+		// Not a JSON object (e.g. an empty-input tool); nothing to redact.
+		return jb
+	}
+
+	for key := range m {
+		if r.fields[strings.ToLower(key)] {
+			m[key] = json.RawMessage(`"[redacted]"`)
+		}
+	}
+
+	redacted, err := json.Marshal(m)
+	if err != nil {
+		return jb
+	}
+	return redacted
+}
+
+// stderrAuditSink writes one JSON record per line to stderr.
+type stderrAuditSink struct {
+	mu sync.Mutex
+}
+
+func (s *stderrAuditSink) Write(record AuditRecord) error {
+	jb, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintln(os.Stderr, string(jb))
+	return err
+}
+
+// rotatingFileAuditSink writes one JSON record per line to a file, rotating
+// a non-empty file before a write would take it past maxSizeByte, or once it
+// is older than maxAge.
+type rotatingFileAuditSink struct {
+	path        string
+	maxSizeByte int64
+	maxAge      time.Duration
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingFileAuditSink(path string, maxSizeByte int64, maxAge time.Duration) (*rotatingFileAuditSink, error) {
+	s := &rotatingFileAuditSink{path: path, maxSizeByte: maxSizeByte, maxAge: maxAge}
+	if err := s.openCurrent(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// openedAtMarkerPath is a small sidecar file recording when the current
+// audit file was first opened. It exists because os.FileInfo.ModTime only
+// reflects the last write, not the creation time: if the process restarts
+// and reopens a pre-existing, not-yet-rotated audit file (the normal case
+// for a long-running deployment restarting against a mounted volume),
+// ModTime would read as "now" and --audit-max-age would never fire until
+// another full maxAge elapsed.
+func (s *rotatingFileAuditSink) openedAtMarkerPath() string {
+	return s.path + ".opened-at"
+}
+
+func (s *rotatingFileAuditSink) openCurrent() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file %q: %w", s.path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat audit file %q: %w", s.path, err)
+	}
+
+	s.file = f
+	s.size = info.Size()
+	s.openedAt = s.resolveOpenedAt(info)
+
+	if markerErr := writeOpenedAtMarker(s.openedAtMarkerPath(), s.openedAt); markerErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to persist audit file open time: %v\n", markerErr)
+	}
+	return nil
+}
+
+// resolveOpenedAt determines when the current audit file was first opened.
+// A freshly created file was obviously just opened; otherwise it trusts the
+// marker left by a prior run and only falls back to ModTime (which may
+// already be stale) if no marker exists yet, e.g. the first run after this
+// fix was deployed.
+func (s *rotatingFileAuditSink) resolveOpenedAt(info os.FileInfo) time.Time {
+	if info.Size() == 0 {
+		return time.Now().UTC()
+	}
+	if t, err := readOpenedAtMarker(s.openedAtMarkerPath()); err == nil {
+		return t
+	}
+	return info.ModTime()
+}
+
+func readOpenedAtMarker(path string) (time.Time, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+}
+
+func writeOpenedAtMarker(path string, t time.Time) error {
+	return os.WriteFile(path, []byte(t.Format(time.RFC3339Nano)), 0o644)
+}
+
+func (s *rotatingFileAuditSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	rotated := fmt.Sprintf("%s.%s", s.path, time.Now().UTC().Format("20060102T150405"))
+	if err := os.Rename(s.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit file %q: %w", s.path, err)
+	}
+	// The marker describes the file that was just rotated away; the next
+	// openCurrent call will create a fresh one for the new current file.
+	_ = os.Remove(s.openedAtMarkerPath())
+	return s.openCurrent()
+}
+
+func (s *rotatingFileAuditSink) Write(record AuditRecord) error {
+	jb, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	jb = append(jb, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	needsRotation := (s.maxSizeByte > 0 && s.size > 0 && s.size+int64(len(jb)) > s.maxSizeByte) ||
+		(s.maxAge > 0 && time.Since(s.openedAt) > s.maxAge)
+	if needsRotation {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(jb)
+	s.size += int64(n)
+	return err
+}
+
+// httpAuditSink POSTs each record as a JSON body to a configured endpoint.
+type httpAuditSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (s *httpAuditSink) Write(record AuditRecord) error {
+	jb, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(jb))
+	if err != nil {
+		return fmt.Errorf("failed to POST audit record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// buildAuditSink constructs the AuditSink described by the current viper
+// configuration, or nil if auditing was not configured.
+func buildAuditSink(logger *slog.Logger) (AuditSink, error) {
+	if path := viper.GetString("audit-file"); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create audit directory: %w", err)
+		}
+		maxSize := viper.GetInt64("audit-max-size-bytes")
+		maxAge := viper.GetDuration("audit-max-age")
+		return newRotatingFileAuditSink(path, maxSize, maxAge)
+	}
+
+	if endpoint := viper.GetString("audit-http-endpoint"); endpoint != "" {
+		return &httpAuditSink{endpoint: endpoint, client: http.DefaultClient}, nil
+	}
+
+	if viper.GetBool("enable-command-logging") {
+		if logger != nil {
+			logger.Info("no --audit-file or --audit-http-endpoint configured, auditing to stderr")
+		}
+		return &stderrAuditSink{}, nil
+	}
+
+	return nil, nil
+}
+
+// wrapAudit wraps h so that every call emits an AuditRecord to sink. If sink
+// is nil, h is returned unchanged. Like wrapTool, h and the returned func
+// use a plain, unnamed function type so the result stays assignable to
+// mcp.ToolHandlerFor[T, R] at the mcp.AddTool call sites.
+func wrapAudit[T, R any](name string, h func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error), sink AuditSink, redactor *auditRedactor) func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error) {
+	if sink == nil {
+		return h
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error) {
+		start := time.Now()
+		callCtx, sc := withStatusCapture(ctx)
+		result, out, err := h(callCtx, req, input)
+		record := AuditRecord{
+			Timestamp:    start.UTC(),
+			Tool:         name,
+			SessionID:    sessionID(req),
+			Input:        redactor.redact(input),
+			MyshoesState: sc.code,
+			DurationMS:   time.Since(start).Milliseconds(),
+			Outcome:      "success",
+		}
+		if err != nil {
+			record.Outcome = "error"
+			record.Error = err.Error()
+		}
+
+		if writeErr := sink.Write(record); writeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to write audit record: %v\n", writeErr)
+		}
+
+		return result, out, err
+	}
+}
+
+// sessionID extracts the caller's session identifier from the request, if
+// any, so audit records can be correlated back to a single MCP client.
+func sessionID(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	return req.Session.ID()
+}
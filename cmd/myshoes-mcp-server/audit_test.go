@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// recordingSink is an AuditSink that keeps every record it receives, for
+// assertions in tests.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *recordingSink) Write(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestWrapAuditRecordsMyshoesStatusOnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	client := withStatusRecording(ts.Client())
+	sink := &recordingSink{}
+
+	h := func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, struct{}, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		if err != nil {
+			return nil, struct{}{}, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, struct{}{}, err
+		}
+		defer resp.Body.Close()
+		return nil, struct{}{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	wrapped := wrapAudit("test_tool", h, sink, newAuditRedactor(nil))
+	if _, _, err := wrapped(context.Background(), &mcp.CallToolRequest{}, struct{}{}); err == nil {
+		t.Fatal("expected handler error to propagate")
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("expected 1 audit record, got %d", len(sink.records))
+	}
+	record := sink.records[0]
+	if record.Outcome != "error" {
+		t.Errorf("outcome: got %q, want %q", record.Outcome, "error")
+	}
+	if record.MyshoesState != http.StatusInternalServerError {
+		t.Errorf("myshoes_status: got %d, want %d", record.MyshoesState, http.StatusInternalServerError)
+	}
+}
+
+func TestAuditRedactorRedactsConfiguredField(t *testing.T) {
+	redactor := newAuditRedactor([]string{"github_personal_token"})
+
+	input := struct {
+		Scope               string `json:"scope"`
+		GithubPersonalToken string `json:"github_personal_token"`
+	}{
+		Scope:               "octocat/hello-world",
+		GithubPersonalToken: "ghp_secret",
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(redactor.redact(input), &got); err != nil {
+		t.Fatalf("failed to unmarshal redacted input: %v", err)
+	}
+	if got["github_personal_token"] != "[redacted]" {
+		t.Errorf("github_personal_token: got %q, want %q", got["github_personal_token"], "[redacted]")
+	}
+	if got["scope"] != input.Scope {
+		t.Errorf("scope: got %q, want unredacted %q", got["scope"], input.Scope)
+	}
+}
+
+func TestAuditRedactorLeavesUnconfiguredFieldsAlone(t *testing.T) {
+	redactor := newAuditRedactor(nil)
+
+	input := struct {
+		Token string `json:"token"`
+	}{Token: "super-secret"}
+
+	var got map[string]string
+	if err := json.Unmarshal(redactor.redact(input), &got); err != nil {
+		t.Fatalf("failed to unmarshal redacted input: %v", err)
+	}
+	if got["token"] != "super-secret" {
+		t.Errorf("token: got %q, want it left untouched since no redaction rule was configured", got["token"])
+	}
+}
+
+func TestRotatingFileAuditSinkRotatesOnSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := newRotatingFileAuditSink(path, 1, 0)
+	if err != nil {
+		t.Fatalf("newRotatingFileAuditSink: %v", err)
+	}
+
+	if err := sink.Write(AuditRecord{Tool: "list_target"}); err != nil {
+		t.Fatalf("first Write: %v", err)
+	}
+	// The first write already exceeds maxSizeByte, so the second write must
+	// rotate the file that holds it out of the way before appending.
+	if err := sink.Write(AuditRecord{Tool: "get_target"}); err != nil {
+		t.Fatalf("second Write: %v", err)
+	}
+
+	rotated, err := rotatedAuditFiles(path)
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("expected exactly one rotated file, got %v", rotated)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile current: %v", err)
+	}
+	if !bytes.Contains(current, []byte("get_target")) {
+		t.Errorf("expected current audit file to hold the post-rotation record, got %q", current)
+	}
+}
+
+func TestRotatingFileAuditSinkRotatesOnAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := newRotatingFileAuditSink(path, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("newRotatingFileAuditSink: %v", err)
+	}
+
+	if err := sink.Write(AuditRecord{Tool: "list_target"}); err != nil {
+		t.Fatalf("first Write: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if err := sink.Write(AuditRecord{Tool: "get_target"}); err != nil {
+		t.Fatalf("second Write: %v", err)
+	}
+
+	rotated, err := rotatedAuditFiles(path)
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("expected exactly one rotated file, got %v", rotated)
+	}
+}
+
+// rotatedAuditFiles returns the rotated copies of path, excluding the
+// "opened-at" marker sidecar that also matches a "path.*" glob.
+func rotatedAuditFiles(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated []string
+	for _, m := range matches {
+		if !strings.HasSuffix(m, ".opened-at") {
+			rotated = append(rotated, m)
+		}
+	}
+	return rotated, nil
+}
+
+func TestRotatingFileAuditSinkSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	first, err := newRotatingFileAuditSink(path, 0, time.Hour)
+	if err != nil {
+		t.Fatalf("newRotatingFileAuditSink: %v", err)
+	}
+	if err := first.Write(AuditRecord{Tool: "list_target"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	originalOpenedAt := first.openedAt
+
+	// Simulate a process restart reopening the same, not-yet-rotated file.
+	// Without a persisted open time, the reopened sink would read the
+	// file's ModTime (effectively "now") instead of when it was first
+	// created, silently defeating --audit-max-age.
+	second, err := newRotatingFileAuditSink(path, 0, time.Hour)
+	if err != nil {
+		t.Fatalf("newRotatingFileAuditSink on restart: %v", err)
+	}
+
+	if !second.openedAt.Equal(originalOpenedAt) {
+		t.Errorf("openedAt after restart = %s, want original open time %s", second.openedAt, originalOpenedAt)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Authenticator injects credentials into an outgoing request before it is
+// sent to the myshoes API.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// authRoundTripper wraps an underlying http.RoundTripper, giving an
+// Authenticator a chance to mutate a clone of the request before it is sent.
+type authRoundTripper struct {
+	next http.RoundTripper
+	auth Authenticator
+}
+
+func (t *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	if err := t.auth.Authenticate(clone); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	return t.next.RoundTrip(clone)
+}
+
+// newAuthenticatedClient returns a copy of base whose Transport runs every
+// outgoing request through auth. If auth is nil, base is returned unchanged.
+func newAuthenticatedClient(base *http.Client, auth Authenticator) *http.Client {
+	if auth == nil {
+		return base
+	}
+
+	next := base.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	client := *base
+	client.Transport = &authRoundTripper{next: next, auth: auth}
+	return &client
+}
+
+// bearerTokenAuthenticator sets a static "Authorization: Bearer <token>" header.
+type bearerTokenAuthenticator struct {
+	token string
+}
+
+func (a bearerTokenAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// basicAuthAuthenticator sets HTTP Basic authentication credentials.
+type basicAuthAuthenticator struct {
+	username string
+	password string
+}
+
+func (a basicAuthAuthenticator) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+// staticHeaderAuthenticator sets a single fixed header, e.g. an API-key header.
+type staticHeaderAuthenticator struct {
+	name  string
+	value string
+}
+
+func (a staticHeaderAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set(a.name, a.value)
+	return nil
+}
+
+// fileTokenAuthenticator reads a bearer token from a file and transparently
+// reloads it whenever the file's modification time changes, so a long-running
+// HTTP-transport deployment can have its credentials rotated without a restart.
+type fileTokenAuthenticator struct {
+	path    string
+	logger  *slog.Logger
+	cached  atomic.Value // string
+	modTime atomic.Value // time.Time
+}
+
+func newFileTokenAuthenticator(path string, logger *slog.Logger) (*fileTokenAuthenticator, error) {
+	a := &fileTokenAuthenticator{path: path, logger: logger}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *fileTokenAuthenticator) reload() error {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat token file %q: %w", a.path, err)
+	}
+
+	if cached, ok := a.modTime.Load().(time.Time); ok && cached.Equal(info.ModTime()) {
+		return nil
+	}
+
+	b, err := os.ReadFile(a.path)
+	if err != nil {
+		return fmt.Errorf("failed to read token file %q: %w", a.path, err)
+	}
+
+	a.cached.Store(strings.TrimSpace(string(b)))
+	a.modTime.Store(info.ModTime())
+	if a.logger != nil {
+		a.logger.Info("reloaded token file", slog.String("path", a.path))
+	}
+	return nil
+}
+
+func (a *fileTokenAuthenticator) Authenticate(req *http.Request) error {
+	if err := a.reload(); err != nil {
+		// Keep using the last known-good token rather than failing the
+		// request outright if the file is briefly unavailable mid-rotation.
+		if a.logger != nil {
+			a.logger.Warn("failed to reload token file, reusing cached token", slog.String("error", err.Error()))
+		}
+	}
+
+	token, _ := a.cached.Load().(string)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// buildAuthenticator constructs the Authenticator described by the current
+// viper configuration, or nil if no authentication was configured.
+func buildAuthenticator(logger *slog.Logger) (Authenticator, error) {
+	if tokenFile := viper.GetString("token-file"); tokenFile != "" {
+		return newFileTokenAuthenticator(tokenFile, logger)
+	}
+
+	if token := viper.GetString("token"); token != "" {
+		return bearerTokenAuthenticator{token: token}, nil
+	}
+
+	user := viper.GetString("basic-auth-user")
+	pass := viper.GetString("basic-auth-pass")
+	if user != "" || pass != "" {
+		return basicAuthAuthenticator{username: user, password: pass}, nil
+	}
+
+	headerName := viper.GetString("header-name")
+	headerValue := viper.GetString("header-value")
+	if headerName != "" {
+		return staticHeaderAuthenticator{name: headerName, value: headerValue}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// resetAuthFlags clears every viper key buildAuthenticator consults, so
+// test cases don't leak into one another.
+func resetAuthFlags(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"token-file", "token", "basic-auth-user", "basic-auth-pass", "header-name", "header-value"} {
+		viper.Set(key, "")
+	}
+	t.Cleanup(func() {
+		for _, key := range []string{"token-file", "token", "basic-auth-user", "basic-auth-pass", "header-name", "header-value"} {
+			viper.Set(key, "")
+		}
+	})
+}
+
+func TestBuildAuthenticatorPrecedence(t *testing.T) {
+	t.Run("no config returns nil", func(t *testing.T) {
+		resetAuthFlags(t)
+		auth, err := buildAuthenticator(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if auth != nil {
+			t.Errorf("expected nil authenticator, got %T", auth)
+		}
+	})
+
+	t.Run("token-file takes precedence over token", func(t *testing.T) {
+		resetAuthFlags(t)
+		path := filepath.Join(t.TempDir(), "token")
+		if err := os.WriteFile(path, []byte("file-token"), 0o600); err != nil {
+			t.Fatalf("failed to write token file: %v", err)
+		}
+		viper.Set("token-file", path)
+		viper.Set("token", "static-token")
+
+		auth, err := buildAuthenticator(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := auth.(*fileTokenAuthenticator); !ok {
+			t.Fatalf("expected *fileTokenAuthenticator, got %T", auth)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		if err := auth.Authenticate(req); err != nil {
+			t.Fatalf("Authenticate: %v", err)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer file-token" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer file-token")
+		}
+	})
+
+	t.Run("token takes precedence over basic auth", func(t *testing.T) {
+		resetAuthFlags(t)
+		viper.Set("token", "static-token")
+		viper.Set("basic-auth-user", "u")
+
+		auth, err := buildAuthenticator(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := auth.(bearerTokenAuthenticator); !ok {
+			t.Fatalf("expected bearerTokenAuthenticator, got %T", auth)
+		}
+	})
+
+	t.Run("basic auth takes precedence over static header", func(t *testing.T) {
+		resetAuthFlags(t)
+		viper.Set("basic-auth-user", "u")
+		viper.Set("basic-auth-pass", "p")
+		viper.Set("header-name", "X-API-Key")
+
+		auth, err := buildAuthenticator(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := auth.(basicAuthAuthenticator); !ok {
+			t.Fatalf("expected basicAuthAuthenticator, got %T", auth)
+		}
+	})
+
+	t.Run("falls back to static header", func(t *testing.T) {
+		resetAuthFlags(t)
+		viper.Set("header-name", "X-API-Key")
+		viper.Set("header-value", "secret")
+
+		auth, err := buildAuthenticator(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := auth.(staticHeaderAuthenticator); !ok {
+			t.Fatalf("expected staticHeaderAuthenticator, got %T", auth)
+		}
+	})
+}
+
+func TestFileTokenAuthenticatorReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	a, err := newFileTokenAuthenticator(path, nil)
+	if err != nil {
+		t.Fatalf("newFileTokenAuthenticator: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := a.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer first" {
+		t.Fatalf("Authorization header = %q, want %q", got, "Bearer first")
+	}
+
+	// Rewrite the file with new contents, then bump the mtime so reload
+	// definitely sees a change.
+	if err := os.WriteFile(path, []byte("second"), 0o600); err != nil {
+		t.Fatalf("failed to rewrite token file: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("failed to bump mtime: %v", err)
+	}
+
+	req2, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := a.Authenticate(req2); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got := req2.Header.Get("Authorization"); got != "Bearer second" {
+		t.Errorf("Authorization header after reload = %q, want %q", got, "Bearer second")
+	}
+}
+
+func TestFileTokenAuthenticatorKeepsCachedTokenIfFileDisappears(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("cached"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	a, err := newFileTokenAuthenticator(path, nil)
+	if err != nil {
+		t.Fatalf("newFileTokenAuthenticator: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove token file: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := a.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer cached" {
+		t.Errorf("Authorization header = %q, want cached token to survive a missing file", got)
+	}
+}
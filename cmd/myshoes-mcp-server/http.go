@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	myshoesapi "github.com/whywaita/myshoes/api/myshoes"
+)
+
+var httpCmd = &cobra.Command{
+	Use:   "http",
+	Short: "Start HTTP/SSE server",
+	Long:  `Start a server that communicates over the MCP Streamable HTTP transport, suitable for running as a long-lived, multi-client daemon.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		logger := initLogger()
+
+		cfg := runConfig{logger: logger}
+		if err := runHTTPServer(cfg); err != nil {
+			logger.Error("failed to run HTTP server", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	httpCmd.Flags().String("addr", ":8080", "Address for the HTTP/SSE server to listen on")
+	httpCmd.Flags().String("tls-cert", "", "Path to a TLS certificate file; when set together with --tls-key, the server listens with TLS")
+	httpCmd.Flags().String("tls-key", "", "Path to a TLS private key file; when set together with --tls-cert, the server listens with TLS")
+	httpCmd.Flags().String("base-path", "/", "Base path the MCP endpoint is served under")
+
+	_ = viper.BindPFlag("addr", httpCmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("tls-cert", httpCmd.Flags().Lookup("tls-cert"))
+	_ = viper.BindPFlag("tls-key", httpCmd.Flags().Lookup("tls-key"))
+	_ = viper.BindPFlag("base-path", httpCmd.Flags().Lookup("base-path"))
+}
+
+func runHTTPServer(cfg runConfig) error {
+	host := viper.GetString("host")
+	if host == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	auth, err := buildAuthenticator(cfg.logger)
+	if err != nil {
+		return fmt.Errorf("failed to build authenticator: %w", err)
+	}
+	httpClient := withStatusRecording(newAuthenticatedClient(http.DefaultClient, auth))
+
+	myshoesClient, err := myshoesapi.NewClient(host, httpClient, log.New(io.Discard, "", log.LstdFlags))
+	if err != nil {
+		return fmt.Errorf("failed to create myshoes client: %w", err)
+	}
+
+	myshoesClient.UserAgent = fmt.Sprintf("myshoes-mcp-server/%s", version)
+
+	audit, err := buildAuditSink(cfg.logger)
+	if err != nil {
+		return fmt.Errorf("failed to build audit sink: %w", err)
+	}
+
+	mms := &MyshoesMCPServer{
+		logger:                cfg.logger,
+		client:                myshoesClient,
+		audit:                 audit,
+		redactor:              newAuditRedactor(viper.GetStringSlice("audit-redact-fields")),
+		enableTargetMutations: viper.GetBool("enable-target-mutations"),
+	}
+
+	basePath := viper.GetString("base-path")
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		server := mcp.NewServer(&mcp.Implementation{
+			Name:    "myshoes-mcp-server",
+			Version: "1.0.0",
+		}, nil)
+		registerTools(server, mms)
+		registerResources(server, mms)
+		return server
+	}, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle(basePath, handler)
+
+	addr := viper.GetString("addr")
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	tlsCert := viper.GetString("tls-cert")
+	tlsKey := viper.GetString("tls-key")
+
+	if tlsCert != "" && tlsKey != "" {
+		cfg.logger.Info("starting HTTP server with TLS", slog.String("addr", addr), slog.String("base_path", basePath))
+		return srv.ListenAndServeTLS(tlsCert, tlsKey)
+	}
+
+	cfg.logger.Info("starting HTTP server", slog.String("addr", addr), slog.String("base_path", basePath))
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+// statusCapture records the HTTP status code of the most recent myshoes API
+// response made within a context. It exists because the myshoes API client
+// does not expose a typed error carrying the response status, so callers
+// that only see a wrapped error (the retry policy, the audit sink) recover
+// the status out-of-band instead of guessing at the error's shape.
+type statusCapture struct {
+	code int
+}
+
+type statusCaptureKey struct{}
+
+// withStatusCapture returns ctx annotated with a statusCapture that
+// statusRoundTripper will populate on the next request made with it, along
+// with the capture itself. If ctx already carries one (e.g. an outer
+// decorator attached it first), that same capture is reused so every layer
+// observes the same status.
+func withStatusCapture(ctx context.Context) (context.Context, *statusCapture) {
+	if sc, ok := ctx.Value(statusCaptureKey{}).(*statusCapture); ok {
+		return ctx, sc
+	}
+	sc := &statusCapture{}
+	return context.WithValue(ctx, statusCaptureKey{}, sc), sc
+}
+
+// statusRoundTripper records the status code of every response it sees into
+// the statusCapture attached to the request's context, if any.
+type statusRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *statusRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if resp != nil {
+		if sc, ok := req.Context().Value(statusCaptureKey{}).(*statusCapture); ok {
+			sc.code = resp.StatusCode
+		}
+	}
+	return resp, err
+}
+
+// withStatusRecording returns a copy of base whose Transport records the
+// status code of every response into the request's statusCapture, if any.
+func withStatusRecording(base *http.Client) *http.Client {
+	next := base.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	client := *base
+	client.Transport = &statusRoundTripper{next: next}
+	return &client
+}
@@ -8,10 +8,10 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
-	"github.com/goccy/go-json"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -37,10 +37,9 @@ var (
 		Run: func(_ *cobra.Command, _ []string) {
 			logger := initLogger()
 
-			logCommands := viper.GetBool("enable-command-logging")
 			cfg := runConfig{
-				logger:      logger,
-				logCommands: logCommands,
+				logger:          logger,
+				logRawTransport: viper.GetBool("debug-raw-transport-log"),
 			}
 			if err := runStdioServer(cfg); err != nil {
 				logger.Error("failed to run stdio server", slog.String("error", err.Error()))
@@ -56,21 +55,60 @@ func init() {
 	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
 
 	// Add global flags that will be shared by all commands
-	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled, the server will log all command requests and responses to the log file")
+	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled and no --audit-file or --audit-http-endpoint is set, audit records are written to stderr instead of being dropped")
+	rootCmd.PersistentFlags().Bool("debug-raw-transport-log", false, "DEBUG ONLY: dump the raw, unredacted JSON-RPC wire traffic to stderr (stdio transport only). Bypasses --audit-redact-fields entirely, so secrets passed as tool arguments will be logged in the clear; never enable this where that log could leak")
 	rootCmd.PersistentFlags().String("host", "", "Specify the myshoes host")
+	rootCmd.PersistentFlags().String("token", "", "Bearer token used to authenticate against the myshoes API (env: MYSHOES_TOKEN)")
+	rootCmd.PersistentFlags().String("token-file", "", "Path to a file containing a bearer token; the file is re-read whenever its contents change")
+	rootCmd.PersistentFlags().String("basic-auth-user", "", "Username for HTTP Basic authentication against the myshoes API")
+	rootCmd.PersistentFlags().String("basic-auth-pass", "", "Password for HTTP Basic authentication against the myshoes API")
+	rootCmd.PersistentFlags().String("header-name", "", "Name of a static header to send with every request to the myshoes API")
+	rootCmd.PersistentFlags().String("header-value", "", "Value of the static header named by --header-name")
+	rootCmd.PersistentFlags().String("config", "", "Path to a config file holding per-tool timeout overrides")
+	rootCmd.PersistentFlags().Duration("tool-timeout", defaultToolTimeout, "Default per-tool call timeout")
+	rootCmd.PersistentFlags().String("audit-file", "", "Path to a rotating audit log file; when set, every tool call is recorded as a JSON line")
+	rootCmd.PersistentFlags().Int64("audit-max-size-bytes", 100*1024*1024, "Rotate the audit file once it exceeds this size in bytes")
+	rootCmd.PersistentFlags().Duration("audit-max-age", 24*time.Hour, "Rotate the audit file once it is older than this duration")
+	rootCmd.PersistentFlags().String("audit-http-endpoint", "", "URL to POST each audit record to, as an alternative to --audit-file")
+	rootCmd.PersistentFlags().StringSlice("audit-redact-fields", nil, "Tool input field names to redact from audit records")
+	rootCmd.PersistentFlags().Bool("enable-target-mutations", false, "Register the create_target, update_target, and delete_target tools, which create, modify, or permanently delete myshoes targets. Off by default; the read-only list_target and get_target tools are always available")
 
 	// Bind flag to viper
 	_ = viper.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
+	_ = viper.BindPFlag("debug-raw-transport-log", rootCmd.PersistentFlags().Lookup("debug-raw-transport-log"))
 	_ = viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
+	_ = viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+	_ = viper.BindPFlag("token-file", rootCmd.PersistentFlags().Lookup("token-file"))
+	_ = viper.BindPFlag("basic-auth-user", rootCmd.PersistentFlags().Lookup("basic-auth-user"))
+	_ = viper.BindPFlag("basic-auth-pass", rootCmd.PersistentFlags().Lookup("basic-auth-pass"))
+	_ = viper.BindPFlag("header-name", rootCmd.PersistentFlags().Lookup("header-name"))
+	_ = viper.BindPFlag("header-value", rootCmd.PersistentFlags().Lookup("header-value"))
+	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	_ = viper.BindPFlag("tool-timeout", rootCmd.PersistentFlags().Lookup("tool-timeout"))
+	_ = viper.BindPFlag("audit-file", rootCmd.PersistentFlags().Lookup("audit-file"))
+	_ = viper.BindPFlag("audit-max-size-bytes", rootCmd.PersistentFlags().Lookup("audit-max-size-bytes"))
+	_ = viper.BindPFlag("audit-max-age", rootCmd.PersistentFlags().Lookup("audit-max-age"))
+	_ = viper.BindPFlag("audit-http-endpoint", rootCmd.PersistentFlags().Lookup("audit-http-endpoint"))
+	_ = viper.BindPFlag("audit-redact-fields", rootCmd.PersistentFlags().Lookup("audit-redact-fields"))
+	_ = viper.BindPFlag("enable-target-mutations", rootCmd.PersistentFlags().Lookup("enable-target-mutations"))
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(httpCmd)
 }
 
 func initConfig() {
 	// Initialize Viper configuration
 	viper.SetEnvPrefix("myshoes")
 	viper.AutomaticEnv()
+
+	if configFile := viper.GetString("config"); configFile != "" {
+		viper.SetConfigFile(configFile)
+		if err := viper.ReadInConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read config file %q: %v\n", configFile, err)
+			os.Exit(1)
+		}
+	}
 }
 
 func initLogger() *slog.Logger {
@@ -83,13 +121,19 @@ func initLogger() *slog.Logger {
 }
 
 type runConfig struct {
-	logger      *slog.Logger
-	logCommands bool
+	logger *slog.Logger
+	// logRawTransport enables a raw, unredacted dump of JSON-RPC wire traffic
+	// to stderr. It is independent of the structured audit sink and exists
+	// only for local debugging; see --debug-raw-transport-log.
+	logRawTransport bool
 }
 
 type MyshoesMCPServer struct {
-	logger *slog.Logger
-	client *myshoesapi.Client
+	logger                *slog.Logger
+	client                *myshoesapi.Client
+	audit                 AuditSink
+	redactor              *auditRedactor
+	enableTargetMutations bool
 }
 
 func runStdioServer(cfg runConfig) error {
@@ -99,16 +143,30 @@ func runStdioServer(cfg runConfig) error {
 		return fmt.Errorf("host is required")
 	}
 
-	myshoesClient, err := myshoesapi.NewClient(host, http.DefaultClient, log.New(io.Discard, "", log.LstdFlags))
+	auth, err := buildAuthenticator(cfg.logger)
+	if err != nil {
+		return fmt.Errorf("failed to build authenticator: %w", err)
+	}
+	httpClient := withStatusRecording(newAuthenticatedClient(http.DefaultClient, auth))
+
+	myshoesClient, err := myshoesapi.NewClient(host, httpClient, log.New(io.Discard, "", log.LstdFlags))
 	if err != nil {
 		return fmt.Errorf("failed to create myshoes client: %w", err)
 	}
 
 	myshoesClient.UserAgent = fmt.Sprintf("myshoes-mcp-server/%s", version)
 
-	mms := MyshoesMCPServer{
-		logger: cfg.logger,
-		client: myshoesClient,
+	audit, err := buildAuditSink(cfg.logger)
+	if err != nil {
+		return fmt.Errorf("failed to build audit sink: %w", err)
+	}
+
+	mms := &MyshoesMCPServer{
+		logger:                cfg.logger,
+		client:                myshoesClient,
+		audit:                 audit,
+		redactor:              newAuditRedactor(viper.GetStringSlice("audit-redact-fields")),
+		enableTargetMutations: viper.GetBool("enable-target-mutations"),
 	}
 
 	// Create MCP server using the official SDK
@@ -117,15 +175,14 @@ func runStdioServer(cfg runConfig) error {
 		Version: "1.0.0",
 	}, nil)
 
-	// Add the list_target tool
-	mcp.AddTool(myshoesServer, &mcp.Tool{
-		Name:        "list_target",
-		Description: "List target from myshoes API",
-	}, mms.listTargetHandler)
+	registerTools(myshoesServer, mms)
+	registerResources(myshoesServer, mms)
 
 	// Start stdio transport
 	var transport mcp.Transport = mcp.NewStdioTransport()
-	if cfg.logCommands {
+	if cfg.logRawTransport {
+		// This dumps the raw wire traffic and is not subject to
+		// --audit-redact-fields; see the flag's help text.
 		transport = mcp.NewLoggingTransport(transport, os.Stderr)
 	}
 
@@ -133,32 +190,6 @@ func runStdioServer(cfg runConfig) error {
 	return myshoesServer.Run(context.Background(), transport)
 }
 
-// ListTargetArgs defines the input arguments for list_target tool (empty in this case)
-type ListTargetArgs struct{}
-
-func (mms MyshoesMCPServer) listTargetHandler(ctx context.Context, _ *mcp.ServerSession, _ *mcp.CallToolParamsFor[ListTargetArgs]) (*mcp.CallToolResultFor[struct{}], error) {
-	targets, err := mms.client.ListTarget(ctx)
-	if err != nil {
-		mms.logger.Warn("failed to list targets", slog.String("error", err.Error()))
-		return nil, fmt.Errorf("failed to list targets: %w", err)
-	}
-
-	jb, err := json.Marshal(targets)
-	if err != nil {
-		mms.logger.Warn("failed to marshal targets", slog.String("error", err.Error()))
-		return nil, fmt.Errorf("failed to marshal targets: %w", err)
-	}
-
-	// Return the result with text content
-	return &mcp.CallToolResultFor[struct{}]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(jb),
-			},
-		},
-	}, nil
-}
-
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
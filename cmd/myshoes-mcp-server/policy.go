@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultToolTimeout = 30 * time.Second
+	maxRetryAttempts   = 3
+	retryBaseDelay     = 200 * time.Millisecond
+	retryMaxJitter     = 100 * time.Millisecond
+)
+
+// idempotentTools lists the tools that are safe to retry automatically on a
+// retryable error, because calling them twice has no side effect beyond the
+// read itself.
+var idempotentTools = map[string]bool{
+	"list_target": true,
+	"get_target":  true,
+}
+
+// wrapTool wraps h with the server's tool policy: a per-tool timeout, and,
+// for idempotent tools, exponential backoff with jitter on retryable errors.
+// Cancellation from the MCP client is inherited for free because the timeout
+// context is derived from the request's own context.
+//
+// h and the returned func deliberately have a plain, unnamed function type
+// rather than a named one: mcp.AddTool requires its handler argument to be
+// assignable to mcp.ToolHandlerFor[T, R], and Go only allows that assignment
+// when at least one side is an unnamed type.
+func wrapTool[T, R any](name string, h func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error), logger *slog.Logger) func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error) {
+		timeout := toolTimeout(name)
+
+		if !idempotentTools[name] {
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return h(callCtx, req, input)
+		}
+
+		var (
+			result *mcp.CallToolResult
+			out    R
+			err    error
+		)
+		for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			callCtx, sc := withStatusCapture(callCtx)
+			result, out, err = h(callCtx, req, input)
+			cancel()
+
+			if err == nil || !isRetryableError(err, sc.code) {
+				return result, out, err
+			}
+
+			if attempt == maxRetryAttempts-1 {
+				break
+			}
+
+			delay := retryBaseDelay*time.Duration(1<<attempt) + time.Duration(rand.Int63n(int64(retryMaxJitter)))
+			if logger != nil {
+				logger.Warn("retrying tool call after retryable error",
+					slog.String("tool", name), slog.Int("attempt", attempt+1), slog.String("error", err.Error()))
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, out, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		return result, out, err
+	}
+}
+
+// toolTimeout resolves the effective timeout for a tool: a per-tool override
+// from the config file (tool-timeouts.<name>), falling back to --tool-timeout,
+// falling back to defaultToolTimeout.
+func toolTimeout(name string) time.Duration {
+	if d := viper.GetDuration("tool-timeouts." + name); d > 0 {
+		return d
+	}
+	if d := viper.GetDuration("tool-timeout"); d > 0 {
+		return d
+	}
+	return defaultToolTimeout
+}
+
+// isRetryableError reports whether err looks transient: a network-level
+// error, or a myshoes API response with a 5xx status. Explicit 4xx responses
+// from myshoes (bad input, not found, ...) are never retried. statusCode is
+// the status of the response that produced err, as observed directly off
+// the wire by statusRoundTripper (0 if no response was ever received); the
+// myshoes client does not expose a typed error carrying the status, so this
+// is read out-of-band rather than guessed from err's shape.
+func isRetryableError(err error, statusCode int) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if statusCode >= 500 {
+		return true
+	}
+	if statusCode >= 400 {
+		return false
+	}
+
+	// Fall back to a conservative substring check for lower-level transport
+	// failures that don't surface as a typed net.Error.
+	msg := err.Error()
+	return strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "EOF")
+}
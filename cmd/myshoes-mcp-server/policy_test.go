@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		want       bool
+	}{
+		{"network error", &net.DNSError{IsTimeout: true}, 0, true},
+		{"5xx status", errors.New("myshoes API returned an error"), 503, true},
+		{"4xx status", errors.New("myshoes API returned an error"), 404, false},
+		{"connection refused substring", errors.New("dial tcp: connection refused"), 0, true},
+		{"unrelated error", errors.New("boom"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err, tt.statusCode); got != tt.want {
+				t.Errorf("isRetryableError(%v, %d) = %v, want %v", tt.err, tt.statusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+// fetchHandler is a minimal handler that performs a GET against url through
+// client and turns a non-2xx response into an error, so wrapTool's retry
+// loop has something real to exercise end-to-end.
+func fetchHandler(client *http.Client, url string) func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, struct{}, error) {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, struct{}, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, struct{}{}, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, struct{}{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return nil, struct{}{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return &mcp.CallToolResult{}, struct{}{}, nil
+	}
+}
+
+func TestWrapToolRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := withStatusRecording(ts.Client())
+
+	const toolName = "test_retry_tool"
+	idempotentTools[toolName] = true
+	defer delete(idempotentTools, toolName)
+
+	wrapped := wrapTool(toolName, fetchHandler(client, ts.URL), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, _, err := wrapped(context.Background(), &mcp.CallToolRequest{}, struct{}{}); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected 3 calls (2 failed + 1 success), got %d", got)
+	}
+}
+
+func TestWrapToolDoesNotRetryOn4xx(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := withStatusRecording(ts.Client())
+
+	const toolName = "test_no_retry_tool"
+	idempotentTools[toolName] = true
+	defer delete(idempotentTools, toolName)
+
+	wrapped := wrapTool(toolName, fetchHandler(client, ts.URL), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, _, err := wrapped(context.Background(), &mcp.CallToolRequest{}, struct{}{}); err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected exactly 1 call for a non-retryable status, got %d", got)
+	}
+}
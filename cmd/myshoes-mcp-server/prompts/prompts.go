@@ -0,0 +1,88 @@
+// Package prompts registers MCP Prompts for common myshoes operator
+// workflows: scripted sequences of tool calls that an LLM client can expand
+// instead of having to re-discover the right sequence of tools every time.
+package prompts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Register adds every myshoes operator prompt to server.
+func Register(server *mcp.Server) {
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "provision_repo_runners",
+		Description: "Guide the creation of a new myshoes target for a repository or organization scope",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "scope", Description: "GitHub repository or organization scope, e.g. octocat/hello-world", Required: true},
+			{Name: "resource_type", Description: "Runner resource type, e.g. nano, small, medium", Required: false},
+			{Name: "provider_url", Description: "Cloud provider URL used to provision runners", Required: false},
+		},
+	}, provisionRepoRunners)
+
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "cleanup_idle_targets",
+		Description: "Walk every myshoes target and propose deleting the ones that have been idle longer than a given number of days",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "idle_days", Description: "Minimum number of idle days before a target is proposed for deletion", Required: false},
+		},
+	}, cleanupIdleTargets)
+}
+
+func provisionRepoRunners(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	scope := req.Params.Arguments["scope"]
+	if scope == "" {
+		return nil, fmt.Errorf("scope argument is required")
+	}
+	resourceType := req.Params.Arguments["resource_type"]
+	providerURL := req.Params.Arguments["provider_url"]
+
+	text := fmt.Sprintf(`Provision myshoes runners for scope %q.
+
+1. Call list_target first and check whether a target for this scope already exists; if it does, stop and report that instead of creating a duplicate.
+2. Otherwise call create_target with scope=%q%s%s.
+3. Confirm the new target by calling get_target with the UUID returned from create_target, and report its status.`,
+		scope, scope, optionalArg("resource_type", resourceType), optionalArg("provider_url", providerURL))
+
+	return &mcp.GetPromptResult{
+		Description: "Provision myshoes runners for a repository or organization scope",
+		Messages: []*mcp.PromptMessage{
+			{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
+			},
+		},
+	}, nil
+}
+
+func cleanupIdleTargets(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	idleDays := req.Params.Arguments["idle_days"]
+	if idleDays == "" {
+		idleDays = "30"
+	}
+
+	text := fmt.Sprintf(`Find myshoes targets that have been idle for more than %s days and propose deleting them.
+
+1. Call list_target to get every target.
+2. For each target, compare its updated_at timestamp against now; a target not updated in the last %s days is idle.
+3. Do not call delete_target yourself. Instead, list the idle targets with their scope and last-activity date, and ask for confirmation before any are deleted.`, idleDays, idleDays)
+
+	return &mcp.GetPromptResult{
+		Description: "Propose cleanup of idle myshoes targets",
+		Messages: []*mcp.PromptMessage{
+			{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
+			},
+		},
+	}, nil
+}
+
+func optionalArg(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(", %s=%s", name, value)
+}
@@ -0,0 +1,82 @@
+package prompts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func getPromptRequest(args map[string]string) *mcp.GetPromptRequest {
+	return &mcp.GetPromptRequest{
+		Params: &mcp.GetPromptParams{Arguments: args},
+	}
+}
+
+func promptText(t *testing.T, result *mcp.GetPromptResult) string {
+	t.Helper()
+	if len(result.Messages) == 0 {
+		t.Fatal("expected at least one message")
+	}
+	content, ok := result.Messages[0].Content.(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected TextContent, got %T", result.Messages[0].Content)
+	}
+	return content.Text
+}
+
+func TestProvisionRepoRunners(t *testing.T) {
+	t.Run("missing scope", func(t *testing.T) {
+		if _, err := provisionRepoRunners(context.Background(), getPromptRequest(nil)); err == nil {
+			t.Fatal("expected error for missing scope")
+		}
+	})
+
+	t.Run("includes optional arguments when set", func(t *testing.T) {
+		result, err := provisionRepoRunners(context.Background(), getPromptRequest(map[string]string{
+			"scope":         "octocat/hello-world",
+			"resource_type": "small",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := promptText(t, result)
+		if !strings.Contains(text, "octocat/hello-world") {
+			t.Errorf("expected prompt text to reference scope, got: %s", text)
+		}
+		if !strings.Contains(text, "resource_type=small") {
+			t.Errorf("expected prompt text to include the optional resource_type arg, got: %s", text)
+		}
+		if strings.Contains(text, "provider_url=") {
+			t.Errorf("expected prompt text to omit unset provider_url arg, got: %s", text)
+		}
+	})
+}
+
+func TestCleanupIdleTargets(t *testing.T) {
+	t.Run("defaults idle_days to 30", func(t *testing.T) {
+		result, err := cleanupIdleTargets(context.Background(), getPromptRequest(nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := promptText(t, result)
+		if !strings.Contains(text, "30 days") {
+			t.Errorf("expected prompt text to default idle_days to 30, got: %s", text)
+		}
+		if !strings.Contains(text, "Do not call delete_target yourself") {
+			t.Errorf("expected prompt text to require confirmation before deleting, got: %s", text)
+		}
+	})
+
+	t.Run("honors explicit idle_days", func(t *testing.T) {
+		result, err := cleanupIdleTargets(context.Background(), getPromptRequest(map[string]string{"idle_days": "7"}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := promptText(t, result)
+		if !strings.Contains(text, "7 days") {
+			t.Errorf("expected prompt text to use the given idle_days, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/goccy/go-json"
+)
+
+// registerResources wires up every MCP resource exposed by the server,
+// mirroring the read-only view of the tool surface so clients can read a
+// target through the resource protocol instead of calling a tool. Only reads
+// are served: resource subscriptions and update notifications are not offered.
+func registerResources(server *mcp.Server, mms *MyshoesMCPServer) {
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "myshoes://targets/{id}",
+		Name:        "target",
+		Description: "A myshoes target, identified by its UUID",
+		MIMEType:    "application/json",
+	}, mms.targetResourceHandler)
+}
+
+func (mms *MyshoesMCPServer) targetResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	id, err := resourceID(req.Params.URI, "myshoes://targets/")
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := mms.client.GetTarget(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	return marshalResourceResult(req.Params.URI, target)
+}
+
+func resourceID(uri, prefix string) (string, error) {
+	id := strings.TrimPrefix(uri, prefix)
+	if id == "" || id == uri {
+		return "", fmt.Errorf("invalid resource URI %q", uri)
+	}
+	return id, nil
+}
+
+func marshalResourceResult(uri string, v any) (*mcp.ReadResourceResult, error) {
+	jb, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jb),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func TestTargetResourceHandler(t *testing.T) {
+	_, mms := newTestServer(t)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		result, err := mms.targetResourceHandler(ctx, &mcp.ReadResourceRequest{
+			Params: &mcp.ReadResourceParams{URI: "myshoes://targets/" + testTargetID},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got web.UserTarget
+		if err := json.Unmarshal([]byte(result.Contents[0].Text), &got); err != nil {
+			t.Fatalf("failed to unmarshal resource: %v", err)
+		}
+		if got.Scope != testTarget.Scope {
+			t.Errorf("scope: got %q, want %q", got.Scope, testTarget.Scope)
+		}
+	})
+
+	t.Run("invalid_uri", func(t *testing.T) {
+		if _, err := mms.targetResourceHandler(ctx, &mcp.ReadResourceRequest{
+			Params: &mcp.ReadResourceParams{URI: "myshoes://targets/"},
+		}); err == nil {
+			t.Fatal("expected error for empty id")
+		}
+	})
+}
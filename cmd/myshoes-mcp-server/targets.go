@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/goccy/go-json"
+
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// GetTargetInput defines the input arguments for the get_target tool.
+type GetTargetInput struct {
+	TargetID string `json:"target_id" jsonschema:"the UUID of the target to fetch"`
+}
+
+func (mms *MyshoesMCPServer) getTargetHandler(ctx context.Context, _ *mcp.CallToolRequest, input GetTargetInput) (*mcp.CallToolResult, struct{}, error) {
+	target, err := mms.client.GetTarget(ctx, input.TargetID)
+	if err != nil {
+		mms.logger.Warn("failed to get target", slog.String("target_id", input.TargetID), slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	jb, err := json.Marshal(target)
+	if err != nil {
+		mms.logger.Warn("failed to marshal target", slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: string(jb),
+			},
+		},
+	}, struct{}{}, nil
+}
+
+// CreateTargetInput defines the input arguments for the create_target tool.
+type CreateTargetInput struct {
+	Scope        string `json:"scope" jsonschema:"the GitHub repository or organization scope, e.g. octocat/hello-world"`
+	ResourceType string `json:"resource_type,omitempty" jsonschema:"the runner resource type, e.g. nano, small, medium"`
+	ProviderURL  string `json:"provider_url,omitempty" jsonschema:"the cloud provider URL used to provision runners"`
+	RunnerUser   string `json:"runner_user,omitempty" jsonschema:"the OS user the runner agent will run as"`
+}
+
+func (mms *MyshoesMCPServer) createTargetHandler(ctx context.Context, _ *mcp.CallToolRequest, input CreateTargetInput) (*mcp.CallToolResult, struct{}, error) {
+	if input.Scope == "" {
+		return nil, struct{}{}, fmt.Errorf("scope is required")
+	}
+
+	target, err := mms.client.CreateTarget(ctx, web.TargetCreateParam{
+		Scope:        input.Scope,
+		ResourceType: input.ResourceType,
+		ProviderURL:  input.ProviderURL,
+		RunnerUser:   input.RunnerUser,
+	})
+	if err != nil {
+		mms.logger.Warn("failed to create target", slog.String("scope", input.Scope), slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to create target: %w", err)
+	}
+
+	jb, err := json.Marshal(target)
+	if err != nil {
+		mms.logger.Warn("failed to marshal target", slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: string(jb),
+			},
+		},
+	}, struct{}{}, nil
+}
+
+// UpdateTargetInput defines the input arguments for the update_target tool.
+type UpdateTargetInput struct {
+	TargetID     string `json:"target_id" jsonschema:"the UUID of the target to update"`
+	ResourceType string `json:"resource_type,omitempty" jsonschema:"the new runner resource type"`
+	ProviderURL  string `json:"provider_url,omitempty" jsonschema:"the new cloud provider URL"`
+}
+
+func (mms *MyshoesMCPServer) updateTargetHandler(ctx context.Context, _ *mcp.CallToolRequest, input UpdateTargetInput) (*mcp.CallToolResult, struct{}, error) {
+	target, err := mms.client.UpdateTarget(ctx, input.TargetID, web.TargetCreateParam{
+		ResourceType: input.ResourceType,
+		ProviderURL:  input.ProviderURL,
+	})
+	if err != nil {
+		mms.logger.Warn("failed to update target", slog.String("target_id", input.TargetID), slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to update target: %w", err)
+	}
+
+	jb, err := json.Marshal(target)
+	if err != nil {
+		mms.logger.Warn("failed to marshal target", slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to marshal target: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: string(jb),
+			},
+		},
+	}, struct{}{}, nil
+}
+
+// DeleteTargetInput defines the input arguments for the delete_target tool.
+type DeleteTargetInput struct {
+	TargetID string `json:"target_id" jsonschema:"the UUID of the target to delete"`
+}
+
+func (mms *MyshoesMCPServer) deleteTargetHandler(ctx context.Context, _ *mcp.CallToolRequest, input DeleteTargetInput) (*mcp.CallToolResult, struct{}, error) {
+	if err := mms.client.DeleteTarget(ctx, input.TargetID); err != nil {
+		mms.logger.Warn("failed to delete target", slog.String("target_id", input.TargetID), slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to delete target: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("target %s deleted successfully", input.TargetID),
+			},
+		},
+	}, struct{}{}, nil
+}
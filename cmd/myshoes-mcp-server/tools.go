@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/goccy/go-json"
+
+	"github.com/whywaita/myshoes-mcp-server/cmd/myshoes-mcp-server/prompts"
+)
+
+// registerTools wires up every MCP tool exposed by the server. It is the
+// single source of truth for the server's tool surface so that the stdio
+// and HTTP transports never drift apart.
+func registerTools(server *mcp.Server, mms *MyshoesMCPServer) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_target",
+		Description: "List target from myshoes API",
+	}, instrument("list_target", mms.listTargetHandler, mms))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_target",
+		Description: "Get a myshoes target by ID",
+	}, instrument("get_target", mms.getTargetHandler, mms))
+
+	// create_target, update_target, and delete_target mutate or destroy
+	// myshoes targets; they are only registered when an operator has
+	// explicitly opted in via --enable-target-mutations.
+	if mms.enableTargetMutations {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "create_target",
+			Description: "Create a new myshoes target",
+		}, instrument("create_target", mms.createTargetHandler, mms))
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "update_target",
+			Description: "Update an existing myshoes target",
+		}, instrument("update_target", mms.updateTargetHandler, mms))
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "delete_target",
+			Description: "Delete a myshoes target",
+		}, instrument("delete_target", mms.deleteTargetHandler, mms))
+	}
+
+	prompts.Register(server)
+}
+
+// instrument applies the server's shared per-tool policy and audit logging
+// to a handler before it is registered.
+func instrument[T, R any](name string, h func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error), mms *MyshoesMCPServer) func(ctx context.Context, req *mcp.CallToolRequest, input T) (*mcp.CallToolResult, R, error) {
+	return wrapAudit(name, wrapTool(name, h, mms.logger), mms.audit, mms.redactor)
+}
+
+// ListTargetInput defines the input arguments for list_target tool (empty in this case)
+type ListTargetInput struct{}
+
+func (mms *MyshoesMCPServer) listTargetHandler(ctx context.Context, _ *mcp.CallToolRequest, _ ListTargetInput) (*mcp.CallToolResult, struct{}, error) {
+	targets, err := mms.client.ListTarget(ctx)
+	if err != nil {
+		mms.logger.Warn("failed to list targets", slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to list targets: %w", err)
+	}
+
+	jb, err := json.Marshal(targets)
+	if err != nil {
+		mms.logger.Warn("failed to marshal targets", slog.String("error", err.Error()))
+		return nil, struct{}{}, fmt.Errorf("failed to marshal targets: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: string(jb),
+			},
+		},
+	}, struct{}{}, nil
+}